@@ -0,0 +1,76 @@
+package softlayer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Artifact represents a SoftLayer machine image (and any replicas of it)
+// produced by a build.
+type Artifact struct {
+	imageName      string
+	imageId        string
+	datacenterName string
+	datacenters    []string
+	client         *SoftlayerClient
+}
+
+// BuilderId returns the unique ID for the builder that created this artifact.
+func (a *Artifact) BuilderId() string {
+	return BuilderId
+}
+
+// Files returns no files, since a SoftLayer image isn't something you can
+// download directly from the artifact itself.
+func (a *Artifact) Files() []string {
+	return nil
+}
+
+// Id returns the image ID, or, when the image was replicated to additional
+// datacenters, a comma-separated list of "datacenter:imageId" pairs so
+// downstream post-processors can address each replica individually.
+func (a *Artifact) Id() string {
+	if len(a.datacenters) == 0 {
+		return a.imageId
+	}
+
+	pairs := make([]string, len(a.datacenters))
+	for i, datacenter := range a.datacenters {
+		pairs[i] = fmt.Sprintf("%s:%s", datacenter, a.imageId)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (a *Artifact) String() string {
+	return fmt.Sprintf("SoftLayer image: %s (%s)", a.imageName, a.imageId)
+}
+
+// State allows post-processors to reach SoftLayer-specific data that isn't
+// part of the generic packer.Artifact interface.
+func (a *Artifact) State(name string) interface{} {
+	switch name {
+	case "Client":
+		return a.client
+	case "Datacenters":
+		return a.datacenters
+	default:
+		return nil
+	}
+}
+
+// Destroy deletes the image (and all of its replicas) from SoftLayer.
+func (a *Artifact) Destroy() error {
+	datacenters := a.datacenters
+	if len(datacenters) == 0 {
+		datacenters = []string{a.datacenterName}
+	}
+
+	for _, datacenter := range datacenters {
+		if err := a.client.DeleteImage(a.imageId, datacenter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package softlayer
+
+import "testing"
+
+func TestArtifact_Id(t *testing.T) {
+	cases := []struct {
+		name        string
+		imageId     string
+		datacenters []string
+		expected    string
+	}{
+		{
+			name:     "no replication",
+			imageId:  "12345",
+			expected: "12345",
+		},
+		{
+			name:        "replicated to a single additional datacenter",
+			imageId:     "12345",
+			datacenters: []string{"dal09"},
+			expected:    "dal09:12345",
+		},
+		{
+			name:        "replicated to multiple datacenters",
+			imageId:     "12345",
+			datacenters: []string{"dal09", "ams01", "sjc01"},
+			expected:    "dal09:12345,ams01:12345,sjc01:12345",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &Artifact{
+				imageId:     tc.imageId,
+				datacenters: tc.datacenters,
+			}
+
+			if got := a.Id(); got != tc.expected {
+				t.Errorf("Id() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
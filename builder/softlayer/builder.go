@@ -26,29 +26,47 @@ type config struct {
 	BaseImageId      string `mapstructure:"base_image_id"`
 	BaseOsCode       string `mapstructure:"base_os_code"`
 
+	InstanceType         string `mapstructure:"instance_type"`
 	InstanceName         string `mapstructure:"instance_name"`
 	InstanceDomain       string `mapstructure:"instance_domain"`
 	InstanceCpu          int    `mapstructure:"instance_cpu"`
 	InstanceMemory       int64  `mapstructure:"instance_memory"`
 	InstanceNetworkSpeed int    `mapstructure:"instance_network_speed"`
 	InstanceDiskCapacity int    `mapstructure:"instance_disk_capacity"`
-	SshPort              int64  `mapstructure:"ssh_port"`
-	SshUserName          string `mapstructure:"ssh_username"`
-	SshPrivateKeyFile    string `mapstructure:"ssh_private_key_file"`
 
-	RawSshTimeout   string `mapstructure:"ssh_timeout"`
+	Comm CommConfig `mapstructure:",squash"`
+
+	HardwareProcessor       string `mapstructure:"hardware_processor"`
+	HardwareDiskController  string `mapstructure:"hardware_disk_controller"`
+	HardwarePublicBandwidth int    `mapstructure:"hardware_public_bandwidth"`
+	HardwarePortSpeed       int    `mapstructure:"hardware_port_speed"`
+
+	ImageReplicationDatacenters []string `mapstructure:"image_replication_datacenters"`
+
 	RawStateTimeout string `mapstructure:"instance_state_timeout"`
 
-	SshTimeout   time.Duration
 	StateTimeout time.Duration
 
 	tpl *packer.ConfigTemplate
 }
 
 // Image Types
-const IMAGE_TYPE_FLEX     = "flex"
+const IMAGE_TYPE_FLEX = "flex"
 const IMAGE_TYPE_STANDARD = "standard"
 
+// Communicator types
+const (
+	COMMUNICATOR_SSH   = "ssh"
+	COMMUNICATOR_WINRM = "winrm"
+	COMMUNICATOR_NONE  = "none"
+)
+
+// Instance types
+const (
+	INSTANCE_TYPE_VIRTUAL    = "virtual"
+	INSTANCE_TYPE_BARE_METAL = "bare_metal"
+)
+
 // Builder represents a Packer Builder.
 type Builder struct {
 	config config
@@ -89,6 +107,10 @@ func (self *Builder) Prepare(raws ...interface{}) (parms []string, retErr error)
 		self.config.DatacenterName = "ams01"
 	}
 
+	if self.config.InstanceType == "" {
+		self.config.InstanceType = INSTANCE_TYPE_VIRTUAL
+	}
+
 	if self.config.InstanceName == "" {
 		self.config.InstanceName = fmt.Sprintf("packer-softlayer-%s", time.Now().Unix())
 	}
@@ -121,37 +143,25 @@ func (self *Builder) Prepare(raws ...interface{}) (parms []string, retErr error)
 		self.config.InstanceDiskCapacity = 25
 	}
 
-	if self.config.SshPort == 0 {
-		self.config.SshPort = 22
-	}
-
-	if self.config.SshUserName == "" {
-		self.config.SshUserName = "root"
-	}
-
-	if self.config.RawSshTimeout == "" {
-		self.config.RawSshTimeout = "5m"
-	}
-
 	if self.config.RawStateTimeout == "" {
 		self.config.RawStateTimeout = "10m"
 	}
 
 	templates := map[string]*string{
-		"username":               &self.config.Username,
-		"api_key":                &self.config.APIKey,
-		"datacenter_name":        &self.config.DatacenterName,
-		"base_image_id":          &self.config.BaseImageId,
-		"image_name":             &self.config.ImageName,
-		"image_description":      &self.config.ImageDescription,
-		"image_type":             &self.config.ImageType,
-		"base_os_code":           &self.config.BaseOsCode,
-		"instance_name":          &self.config.InstanceName,
-		"instance_domain":        &self.config.InstanceDomain,
-		"ssh_timeout":            &self.config.RawSshTimeout,
-		"instance_state_timeout": &self.config.RawStateTimeout,
-		"ssh_username":           &self.config.SshUserName,
-		"ssh_private_key_file":   &self.config.SshPrivateKeyFile,
+		"username":                 &self.config.Username,
+		"api_key":                  &self.config.APIKey,
+		"datacenter_name":          &self.config.DatacenterName,
+		"base_image_id":            &self.config.BaseImageId,
+		"image_name":               &self.config.ImageName,
+		"image_description":        &self.config.ImageDescription,
+		"image_type":               &self.config.ImageType,
+		"base_os_code":             &self.config.BaseOsCode,
+		"instance_name":            &self.config.InstanceName,
+		"instance_domain":          &self.config.InstanceDomain,
+		"instance_state_timeout":   &self.config.RawStateTimeout,
+		"instance_type":            &self.config.InstanceType,
+		"hardware_processor":       &self.config.HardwareProcessor,
+		"hardware_disk_controller": &self.config.HardwareDiskController,
 	}
 
 	for n, ptr := range templates {
@@ -162,6 +172,10 @@ func (self *Builder) Prepare(raws ...interface{}) (parms []string, retErr error)
 		}
 	}
 
+	for _, err := range self.config.Comm.Prepare(self.config.tpl) {
+		errs = packer.MultiErrorAppend(errs, err)
+	}
+
 	// Check for required configurations that will display errors if not set
 	if self.config.APIKey == "" {
 		errs = packer.MultiErrorAppend(
@@ -193,20 +207,22 @@ func (self *Builder) Prepare(raws ...interface{}) (parms []string, retErr error)
 			errs, errors.New("please specify only one of base_image_id or base_os_code"))
 	}
 
-	if self.config.BaseImageId != "" && self.config.SshPrivateKeyFile == "" {
+	if self.config.Comm.Type == COMMUNICATOR_SSH && self.config.BaseImageId != "" &&
+		self.config.Comm.SSHPrivateKeyFile == "" && self.config.Comm.SSHKeychainId == "" {
 		errs = packer.MultiErrorAppend(
-			errs, errors.New("when using base_image_id, you must specify ssh_private_key_file "+
-				"since automatic ssh key config for custom images isn't supported by SoftLayer API"))
+			errs, errors.New("when using base_image_id, you must specify ssh_private_key_file or "+
+				"ssh_keychain_id since automatic ssh key config for custom images isn't supported by SoftLayer API"))
 	}
 
-	// Translate date configuration data from string to time format
-	sshTimeout, err := time.ParseDuration(self.config.RawSshTimeout)
-	if err != nil {
+	switch self.config.InstanceType {
+	case INSTANCE_TYPE_VIRTUAL, INSTANCE_TYPE_BARE_METAL:
+		// valid
+	default:
 		errs = packer.MultiErrorAppend(
-			errs, fmt.Errorf("Failed parsing ssh_timeout: %s", err))
+			errs, fmt.Errorf("Unknown instance_type '%s'. Must be one of 'virtual' (the default) or 'bare_metal'.", self.config.InstanceType))
 	}
-	self.config.SshTimeout = sshTimeout
 
+	// Translate date configuration data from string to time format
 	stateTimeout, err := time.ParseDuration(self.config.RawStateTimeout)
 	if err != nil {
 		errs = packer.MultiErrorAppend(
@@ -238,20 +254,55 @@ func (self *Builder) Run(ui packer.Ui, hook packer.Hook, cache packer.Cache) (pa
 	state.Put("ui", ui)
 
 	// Build the steps
-	steps := []multistep.Step{
-		&stepCreateSshKey{
-			PrivateKeyFile: self.config.SshPrivateKeyFile,
-		},
-		new(stepCreateInstance),
-		new(stepWaitforInstance),
-		&common.StepConnectSSH{
-			SSHAddress:     sshAddress,
-			SSHConfig:      sshConfig,
-			SSHWaitTimeout: self.config.SshTimeout,
-		},
-		new(common.StepProvision),
-		new(stepCaptureImage),
-	}
+	var steps []multistep.Step
+
+	if self.config.Comm.Type == COMMUNICATOR_SSH {
+		steps = append(steps, &stepCreateSshKey{
+			PrivateKeyFile: self.config.Comm.SSHPrivateKeyFile,
+			KeychainId:     self.config.Comm.SSHKeychainId,
+		})
+	}
+
+	switch self.config.InstanceType {
+	case INSTANCE_TYPE_BARE_METAL:
+		steps = append(steps, new(stepOrderHardware), new(stepWaitforHardware))
+	default:
+		steps = append(steps, new(stepCreateInstance), new(stepWaitforInstance))
+	}
+
+	switch self.config.Comm.Type {
+	case COMMUNICATOR_SSH:
+		steps = append(steps,
+			&common.StepConnectSSH{
+				SSHAddress:     commHost,
+				SSHConfig:      commSSHConfig,
+				SSHWaitTimeout: self.config.Comm.Timeout,
+			},
+			new(common.StepProvision),
+		)
+	case COMMUNICATOR_WINRM:
+		steps = append(steps,
+			new(stepGetPassword),
+			&stepConnectWinRM{
+				WinRMAddress: commHost,
+				WinRMConfig:  commWinRMConfig,
+				WinRMTimeout: self.config.Comm.Timeout,
+			},
+			new(common.StepProvision),
+		)
+	case COMMUNICATOR_NONE:
+		// No remote access requested: skip connecting and provisioning so
+		// that users can drive pure API-based captures.
+	}
+
+	switch self.config.InstanceType {
+	case INSTANCE_TYPE_BARE_METAL:
+		steps = append(steps, new(stepCaptureHardwareImage))
+	default:
+		steps = append(steps, new(stepCaptureImage))
+	}
+
+	steps = append(steps, new(stepReplicateImage))
 
 	// Create the runner which will run the steps we just build
 	self.runner = &multistep.BasicRunner{Steps: steps}
@@ -275,6 +326,10 @@ func (self *Builder) Run(ui packer.Ui, hook packer.Hook, cache packer.Cache) (pa
 		client:         client,
 	}
 
+	if datacenters, ok := state.GetOk("datacenters"); ok {
+		artifact.datacenters = datacenters.([]string)
+	}
+
 	return artifact, nil
 }
 
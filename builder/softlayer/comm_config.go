@@ -0,0 +1,112 @@
+package softlayer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/packer/packer"
+)
+
+// CommConfig groups every field needed to reach a freshly provisioned
+// instance -- over ssh or winrm -- into a single struct that gets squashed
+// into config and defaulted/validated in one place, regardless of which
+// communicator is selected. This mirrors the helper/communicator pattern
+// Packer uses for its other multi-communicator builders.
+type CommConfig struct {
+	Type string `mapstructure:"communicator"`
+
+	SSHUsername       string `mapstructure:"ssh_username"`
+	SSHPort           int64  `mapstructure:"ssh_port"`
+	SSHPrivateKeyFile string `mapstructure:"ssh_private_key_file"`
+	SSHKeychainId     string `mapstructure:"ssh_keychain_id"`
+	SSHAgentAuth      bool   `mapstructure:"ssh_agent_auth"`
+
+	WinRMUser     string `mapstructure:"winrm_username"`
+	WinRMPassword string `mapstructure:"winrm_password"`
+	WinRMPort     int    `mapstructure:"winrm_port"`
+	WinRMUseSSL   bool   `mapstructure:"winrm_use_ssl"`
+	WinRMInsecure bool   `mapstructure:"winrm_insecure"`
+
+	RawTimeout string `mapstructure:"ssh_timeout"`
+
+	Timeout time.Duration
+}
+
+// Prepare defaults, templates, and validates every communicator field,
+// regardless of which communicator ends up being used, and parses Timeout.
+// It follows the same signature convention as helper/communicator.Config.Prepare.
+func (c *CommConfig) Prepare(tpl *packer.ConfigTemplate) []error {
+	if c.Type == "" {
+		c.Type = COMMUNICATOR_SSH
+	}
+
+	if c.SSHPort == 0 {
+		c.SSHPort = 22
+	}
+
+	if c.SSHUsername == "" {
+		c.SSHUsername = "root"
+	}
+
+	if c.WinRMUser == "" {
+		c.WinRMUser = "Administrator"
+	}
+
+	if c.WinRMPort == 0 {
+		if c.WinRMUseSSL {
+			c.WinRMPort = 5986
+		} else {
+			c.WinRMPort = 5985
+		}
+	}
+
+	if c.RawTimeout == "" {
+		c.RawTimeout = "5m"
+	}
+
+	templates := map[string]*string{
+		"communicator":         &c.Type,
+		"ssh_username":         &c.SSHUsername,
+		"ssh_private_key_file": &c.SSHPrivateKeyFile,
+		"ssh_keychain_id":      &c.SSHKeychainId,
+		"winrm_username":       &c.WinRMUser,
+		"winrm_password":       &c.WinRMPassword,
+		"ssh_timeout":          &c.RawTimeout,
+	}
+
+	var errs []error
+	for n, ptr := range templates {
+		var err error
+		*ptr, err = tpl.Process(*ptr, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Error processing %s: %s", n, err))
+		}
+	}
+
+	switch c.Type {
+	case COMMUNICATOR_SSH, COMMUNICATOR_WINRM, COMMUNICATOR_NONE:
+		// valid
+	default:
+		errs = append(errs, fmt.Errorf(
+			"Unknown communicator '%s'. Must be one of 'ssh' (the default), 'winrm', or 'none'.", c.Type))
+	}
+
+	if c.SSHKeychainId != "" && c.SSHAgentAuth {
+		errs = append(errs, errors.New("please specify only one of ssh_keychain_id or ssh_agent_auth"))
+	}
+
+	if c.Type == COMMUNICATOR_SSH && c.SSHKeychainId != "" && c.SSHPrivateKeyFile == "" && !c.SSHAgentAuth {
+		errs = append(errs, errors.New(
+			"when reusing an existing key via ssh_keychain_id with the ssh communicator, "+
+				"you must also specify ssh_private_key_file (matching that key) or ssh_agent_auth"))
+	}
+
+	timeout, err := time.ParseDuration(c.RawTimeout)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("Failed parsing ssh_timeout: %s", err))
+	}
+	c.Timeout = timeout
+
+	return errs
+}
@@ -0,0 +1,91 @@
+package softlayer
+
+import (
+	"testing"
+
+	"github.com/mitchellh/packer/packer"
+)
+
+func testConfigTemplate(t *testing.T) *packer.ConfigTemplate {
+	tpl, err := packer.NewConfigTemplate()
+	if err != nil {
+		t.Fatalf("failed to create config template: %s", err)
+	}
+	return tpl
+}
+
+func TestCommConfig_Prepare(t *testing.T) {
+	cases := []struct {
+		name      string
+		config    CommConfig
+		expectErr bool
+	}{
+		{
+			name:   "defaults to ssh",
+			config: CommConfig{},
+		},
+		{
+			name:   "explicit ssh is valid",
+			config: CommConfig{Type: COMMUNICATOR_SSH},
+		},
+		{
+			name:   "explicit winrm is valid",
+			config: CommConfig{Type: COMMUNICATOR_WINRM},
+		},
+		{
+			name:   "explicit none is valid",
+			config: CommConfig{Type: COMMUNICATOR_NONE},
+		},
+		{
+			name:      "unknown communicator is invalid",
+			config:    CommConfig{Type: "telnet"},
+			expectErr: true,
+		},
+		{
+			name: "ssh_keychain_id with ssh_agent_auth is invalid",
+			config: CommConfig{
+				Type:          COMMUNICATOR_SSH,
+				SSHKeychainId: "12345",
+				SSHAgentAuth:  true,
+			},
+			expectErr: true,
+		},
+		{
+			name: "ssh_keychain_id without ssh_private_key_file or ssh_agent_auth is invalid",
+			config: CommConfig{
+				Type:          COMMUNICATOR_SSH,
+				SSHKeychainId: "12345",
+			},
+			expectErr: true,
+		},
+		{
+			name: "ssh_keychain_id with ssh_private_key_file is valid",
+			config: CommConfig{
+				Type:              COMMUNICATOR_SSH,
+				SSHKeychainId:     "12345",
+				SSHPrivateKeyFile: "/path/to/key",
+			},
+		},
+		{
+			name: "ssh_keychain_id without ssh_private_key_file or ssh_agent_auth is valid for winrm",
+			config: CommConfig{
+				Type:          COMMUNICATOR_WINRM,
+				SSHKeychainId: "12345",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.config
+			errs := c.Prepare(testConfigTemplate(t))
+
+			if tc.expectErr && len(errs) == 0 {
+				t.Errorf("expected an error, got none")
+			}
+			if !tc.expectErr && len(errs) > 0 {
+				t.Errorf("expected no errors, got: %v", errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package softlayer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/communicator/winrm"
+	"github.com/mitchellh/packer/packer"
+	"golang.org/x/crypto/ssh"
+)
+
+// commHost resolves the address to connect to, regardless of which
+// communicator (ssh or winrm) is being used to provision the instance.
+func commHost(state multistep.StateBag) (string, error) {
+	ip, ok := state.GetOk("instance_ip")
+	if !ok {
+		return "", errors.New("instance IP address not available in state")
+	}
+
+	return ip.(string), nil
+}
+
+// commSSHConfig builds the ssh.ClientConfig used by common.StepConnectSSH
+// when communicator == "ssh".
+func commSSHConfig(state multistep.StateBag) (*ssh.ClientConfig, error) {
+	config := state.Get("config").(config)
+
+	if config.Comm.SSHAgentAuth {
+		return agentSshConfig(config.Comm.SSHUsername)
+	}
+
+	signer, ok := state.GetOk("privateKeySigner")
+	if !ok {
+		return nil, errors.New("no ssh private key available to authenticate with")
+	}
+
+	return &ssh.ClientConfig{
+		User: config.Comm.SSHUsername,
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeys(signer.(ssh.Signer)),
+		},
+	}, nil
+}
+
+// commWinRMConfig builds the connection parameters used by stepConnectWinRM
+// when communicator == "winrm".
+func commWinRMConfig(state multistep.StateBag) (*WinRMConfig, error) {
+	config := state.Get("config").(config)
+
+	password := config.Comm.WinRMPassword
+	if password == "" {
+		if p, ok := state.GetOk("winrm_password"); ok {
+			password = p.(string)
+		}
+	}
+
+	return &WinRMConfig{
+		Username: config.Comm.WinRMUser,
+		Password: password,
+		Port:     config.Comm.WinRMPort,
+		UseSSL:   config.Comm.WinRMUseSSL,
+		Insecure: config.Comm.WinRMInsecure,
+	}, nil
+}
+
+// WinRMConfig holds the parameters needed to establish a WinRM connection
+// to a freshly provisioned Windows instance.
+type WinRMConfig struct {
+	Username string
+	Password string
+	Port     int
+	UseSSL   bool
+	Insecure bool
+}
+
+// stepConnectWinRM is the WinRM analogue of common.StepConnectSSH: it waits
+// for a WinRM endpoint to become reachable and stores a packer.Communicator
+// in the state bag for common.StepProvision to use.
+type stepConnectWinRM struct {
+	WinRMAddress func(multistep.StateBag) (string, error)
+	WinRMConfig  func(multistep.StateBag) (*WinRMConfig, error)
+	WinRMTimeout time.Duration
+}
+
+func (s *stepConnectWinRM) Run(state multistep.StateBag) multistep.StepAction {
+	ui := state.Get("ui").(packer.Ui)
+
+	ui.Say("Waiting for WinRM to become available...")
+	comm, err := s.waitForWinRM(state)
+	if err != nil {
+		state.Put("error", err)
+		ui.Error(fmt.Sprintf("Error waiting for WinRM: %s", err))
+		return multistep.ActionHalt
+	}
+
+	state.Put("communicator", comm)
+	return multistep.ActionContinue
+}
+
+func (s *stepConnectWinRM) waitForWinRM(state multistep.StateBag) (packer.Communicator, error) {
+	address, err := s.WinRMAddress(state)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := s.WinRMConfig(state)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &winrm.Endpoint{
+		Host:     address,
+		Port:     cfg.Port,
+		HTTPS:    cfg.UseSSL,
+		Insecure: cfg.Insecure,
+	}
+
+	var comm packer.Communicator
+	deadline := time.Now().Add(s.WinRMTimeout)
+	for time.Now().Before(deadline) {
+		comm, err = winrm.New(&winrm.Config{
+			Endpoint: endpoint,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		})
+		if err == nil {
+			if _, cerr := comm.(*winrm.Communicator).Ping(); cerr == nil {
+				return comm, nil
+			}
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+
+	return nil, fmt.Errorf("timeout waiting for WinRM at %s: %s",
+		net.JoinHostPort(address, strconv.Itoa(cfg.Port)), err)
+}
+
+func (s *stepConnectWinRM) Cleanup(multistep.StateBag) {}
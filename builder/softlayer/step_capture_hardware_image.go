@@ -0,0 +1,58 @@
+package softlayer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+)
+
+// stepCaptureHardwareImage is the bare-metal analogue of stepCaptureImage.
+// Hardware servers are captured through the provisioning image template
+// group API rather than the virtual-guest capture API, so they need their
+// own transaction-polling step.
+type stepCaptureHardwareImage struct{}
+
+func (s *stepCaptureHardwareImage) Run(state multistep.StateBag) multistep.StepAction {
+	config := state.Get("config").(config)
+	client := state.Get("client").(*SoftlayerClient)
+	ui := state.Get("ui").(packer.Ui)
+	hardwareId := state.Get("hardware_id").(string)
+
+	ui.Say(fmt.Sprintf("Capturing image '%s' of hardware server %s...", config.ImageName, hardwareId))
+
+	transactionId, err := client.CaptureHardwareImage(hardwareId, config.ImageName, config.ImageDescription)
+	if err != nil {
+		err := fmt.Errorf("Error starting hardware image capture: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	deadline := time.Now().Add(config.StateTimeout)
+	for time.Now().Before(deadline) {
+		done, imageId, err := client.GetHardwareImageCaptureStatus(transactionId)
+		if err != nil {
+			err := fmt.Errorf("Error checking hardware image capture status: %s", err)
+			state.Put("error", err)
+			ui.Error(err.Error())
+			return multistep.ActionHalt
+		}
+
+		if done {
+			ui.Message(fmt.Sprintf("Image captured: %s", imageId))
+			state.Put("image_id", imageId)
+			return multistep.ActionContinue
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+
+	err = fmt.Errorf("timed out waiting for hardware image capture to complete")
+	state.Put("error", err)
+	ui.Error(err.Error())
+	return multistep.ActionHalt
+}
+
+func (s *stepCaptureHardwareImage) Cleanup(multistep.StateBag) {}
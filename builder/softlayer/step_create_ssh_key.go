@@ -0,0 +1,160 @@
+package softlayer
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// stepCreateSshKey provisions the SSH key that will be installed on the
+// instance. It supports three modes: generate a new keypair, upload the
+// key from an existing PrivateKeyFile, or reuse an existing SoftLayer
+// key referenced by KeychainId. When ssh_agent_auth is set instead, no
+// key material ever needs to be created at all.
+type stepCreateSshKey struct {
+	PrivateKeyFile string
+	KeychainId     string
+
+	keyId       string
+	createdById bool
+}
+
+func (s *stepCreateSshKey) Run(state multistep.StateBag) multistep.StepAction {
+	config := state.Get("config").(config)
+	client := state.Get("client").(*SoftlayerClient)
+	ui := state.Get("ui").(packer.Ui)
+
+	// A private key file authenticates the ssh communicator regardless of
+	// whether we're uploading a new SoftLayer key or reusing one via
+	// KeychainId, so parse it first and stash the signer either way.
+	if s.PrivateKeyFile != "" {
+		ui.Say("Using the provided private key to derive the public key...")
+
+		signer, err := parsePrivateKeyFile(s.PrivateKeyFile)
+		if err != nil {
+			state.Put("error", err)
+			ui.Error(err.Error())
+			return multistep.ActionHalt
+		}
+
+		state.Put("privateKeySigner", signer)
+	}
+
+	if s.KeychainId != "" {
+		ui.Say(fmt.Sprintf("Reusing existing SoftLayer SSH key %s...", s.KeychainId))
+		s.keyId = s.KeychainId
+		state.Put("ssh_key_id", s.keyId)
+		return multistep.ActionContinue
+	}
+
+	if config.Comm.SSHAgentAuth {
+		if os.Getenv("SSH_AUTH_SOCK") == "" {
+			err := fmt.Errorf("ssh_agent_auth is set but SSH_AUTH_SOCK is not; is an ssh-agent running?")
+			state.Put("error", err)
+			ui.Error(err.Error())
+			return multistep.ActionHalt
+		}
+
+		ui.Say("Using local ssh-agent for authentication; skipping key upload")
+		return multistep.ActionContinue
+	}
+
+	var publicKey string
+
+	if s.PrivateKeyFile != "" {
+		signer := state.Get("privateKeySigner").(ssh.Signer)
+		publicKey = string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+	} else {
+		ui.Say("Creating temporary ssh key for instance...")
+
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			err := fmt.Errorf("Error creating temporary ssh key: %s", err)
+			state.Put("error", err)
+			ui.Error(err.Error())
+			return multistep.ActionHalt
+		}
+
+		signer, err := ssh.NewSignerFromKey(privateKey)
+		if err != nil {
+			err := fmt.Errorf("Error creating temporary ssh key: %s", err)
+			state.Put("error", err)
+			ui.Error(err.Error())
+			return multistep.ActionHalt
+		}
+
+		publicKey = string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+		state.Put("privateKeySigner", signer)
+	}
+
+	keyId, err := client.UploadSshKey(config.InstanceName, publicKey)
+	if err != nil {
+		err := fmt.Errorf("Error uploading ssh key to SoftLayer: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	s.keyId = keyId
+	s.createdById = true
+	state.Put("ssh_key_id", s.keyId)
+
+	return multistep.ActionContinue
+}
+
+// parsePrivateKeyFile reads and parses an ssh private key file into a
+// signer that can be used both to derive its public key and to
+// authenticate the ssh communicator.
+func parsePrivateKeyFile(path string) (ssh.Signer, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading private key file: %s", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing private key file: %s", err)
+	}
+
+	return signer, nil
+}
+
+// Cleanup removes the SSH key from SoftLayer, but only if this step is the
+// one that created it; keys reused via KeychainId are left alone.
+func (s *stepCreateSshKey) Cleanup(state multistep.StateBag) {
+	if !s.createdById || s.keyId == "" {
+		return
+	}
+
+	client := state.Get("client").(*SoftlayerClient)
+	ui := state.Get("ui").(packer.Ui)
+
+	ui.Say("Deleting temporary ssh key...")
+	if err := client.DeleteSshKey(s.keyId); err != nil {
+		ui.Error(fmt.Sprintf("Error cleaning up ssh key: %s", err))
+	}
+}
+
+// agentSshConfig builds an ssh.ClientConfig that authenticates via the
+// local ssh-agent, for use when ssh_agent_auth is set.
+func agentSshConfig(user string) (*ssh.ClientConfig, error) {
+	sock, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to ssh-agent: %s", err)
+	}
+
+	return &ssh.ClientConfig{
+		User: user,
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeysCallback(agent.NewClient(sock).Signers),
+		},
+	}, nil
+}
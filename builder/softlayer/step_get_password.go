@@ -0,0 +1,49 @@
+package softlayer
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+)
+
+// stepGetPassword retrieves the generated Windows administrator password
+// for the instance (virtual or bare metal) from SoftLayer's provisioning
+// API and stashes it in the state bag so commWinRMConfig can use it if
+// winrm_password wasn't set explicitly.
+type stepGetPassword struct{}
+
+func (s *stepGetPassword) Run(state multistep.StateBag) multistep.StepAction {
+	config := state.Get("config").(config)
+
+	if config.Comm.WinRMPassword != "" {
+		return multistep.ActionContinue
+	}
+
+	ui := state.Get("ui").(packer.Ui)
+	client := state.Get("client").(*SoftlayerClient)
+
+	ui.Say("Retrieving the Windows administrator password...")
+
+	var password string
+	var err error
+
+	switch config.InstanceType {
+	case INSTANCE_TYPE_BARE_METAL:
+		password, err = client.GetHardwarePassword(state.Get("hardware_id").(string))
+	default:
+		password, err = client.GetInstancePassword(state.Get("instance_id").(string))
+	}
+
+	if err != nil {
+		err := fmt.Errorf("Error retrieving the administrator password: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	state.Put("winrm_password", password)
+	return multistep.ActionContinue
+}
+
+func (s *stepGetPassword) Cleanup(multistep.StateBag) {}
@@ -0,0 +1,87 @@
+package softlayer
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+)
+
+// HardwareOrder describes the SoftLayer_Product_Order parameters needed to
+// provision a bare metal SoftLayer_Hardware_Server.
+type HardwareOrder struct {
+	Hostname        string
+	Domain          string
+	DatacenterName  string
+	Processor       string
+	DiskController  string
+	PublicBandwidth int
+	PortSpeed       int
+	BaseImageId     string
+	BaseOsCode      string
+	SshKeyId        string
+}
+
+// stepOrderHardware is the bare-metal analogue of stepCreateInstance: it
+// places a SoftLayer_Product_Order against SoftLayer_Hardware_Server
+// instead of provisioning a SoftLayer_Virtual_Guest.
+type stepOrderHardware struct{}
+
+func (s *stepOrderHardware) Run(state multistep.StateBag) multistep.StepAction {
+	config := state.Get("config").(config)
+	client := state.Get("client").(*SoftlayerClient)
+	ui := state.Get("ui").(packer.Ui)
+
+	ui.Say(fmt.Sprintf("Placing order for bare metal server %s...", config.InstanceName))
+
+	var sshKeyId string
+	if id, ok := state.GetOk("ssh_key_id"); ok {
+		sshKeyId = id.(string)
+	}
+
+	order := HardwareOrder{
+		Hostname:        config.InstanceName,
+		Domain:          config.InstanceDomain,
+		DatacenterName:  config.DatacenterName,
+		Processor:       config.HardwareProcessor,
+		DiskController:  config.HardwareDiskController,
+		PublicBandwidth: config.HardwarePublicBandwidth,
+		PortSpeed:       config.HardwarePortSpeed,
+		BaseImageId:     config.BaseImageId,
+		BaseOsCode:      config.BaseOsCode,
+		SshKeyId:        sshKeyId,
+	}
+
+	hardwareId, err := client.PlaceHardwareOrder(order)
+	if err != nil {
+		err := fmt.Errorf("Error ordering hardware server: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	ui.Message(fmt.Sprintf("Hardware server ordered: %s", hardwareId))
+	state.Put("hardware_id", hardwareId)
+
+	return multistep.ActionContinue
+}
+
+func (s *stepOrderHardware) Cleanup(state multistep.StateBag) {
+	hardwareId, ok := state.GetOk("hardware_id")
+	if !ok {
+		return
+	}
+
+	_, halted := state.GetOk("error")
+	if !halted {
+		return
+	}
+
+	client := state.Get("client").(*SoftlayerClient)
+	ui := state.Get("ui").(packer.Ui)
+
+	ui.Say("Cancelling bare metal server order...")
+	if err := client.CancelHardware(hardwareId.(string)); err != nil {
+		ui.Error(fmt.Sprintf("Error cancelling hardware server: %s", err))
+	}
+}
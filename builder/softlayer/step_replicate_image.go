@@ -0,0 +1,93 @@
+package softlayer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+)
+
+// stepReplicateImage copies the just-captured image to every datacenter
+// listed in image_replication_datacenters, modeled after the AMI builder's
+// region-copy step: one goroutine per destination, a shared MultiError
+// guarded by a mutex, and per-datacenter polling bounded by StateTimeout.
+type stepReplicateImage struct{}
+
+func (s *stepReplicateImage) Run(state multistep.StateBag) multistep.StepAction {
+	config := state.Get("config").(config)
+	client := state.Get("client").(*SoftlayerClient)
+	ui := state.Get("ui").(packer.Ui)
+	imageId := state.Get("image_id").(string)
+
+	if len(config.ImageReplicationDatacenters) == 0 {
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Replicating image %s to %d datacenter(s)...",
+		imageId, len(config.ImageReplicationDatacenters)))
+
+	var wg sync.WaitGroup
+	var errsMutex sync.Mutex
+	errs := &packer.MultiError{}
+
+	for _, datacenter := range config.ImageReplicationDatacenters {
+		wg.Add(1)
+
+		go func(datacenter string) {
+			defer wg.Done()
+
+			err := replicateImageToDatacenter(client, imageId, datacenter, config.StateTimeout)
+			if err != nil {
+				errsMutex.Lock()
+				errs = packer.MultiErrorAppend(errs,
+					fmt.Errorf("Error replicating image to %s: %s", datacenter, err))
+				errsMutex.Unlock()
+				return
+			}
+
+			ui.Message(fmt.Sprintf("Replication to %s complete", datacenter))
+		}(datacenter)
+	}
+
+	wg.Wait()
+
+	if len(errs.Errors) > 0 {
+		state.Put("error", errs)
+		ui.Error(errs.Error())
+		return multistep.ActionHalt
+	}
+
+	state.Put("datacenters", append([]string{config.DatacenterName}, config.ImageReplicationDatacenters...))
+
+	return multistep.ActionContinue
+}
+
+func (s *stepReplicateImage) Cleanup(multistep.StateBag) {}
+
+// replicateImageToDatacenter invokes setAvailableLocations for the given
+// datacenter and polls the transaction status until it completes or
+// stateTimeout elapses.
+func replicateImageToDatacenter(client *SoftlayerClient, imageId, datacenter string, stateTimeout time.Duration) error {
+	err := client.SetImageAvailableLocations(imageId, datacenter)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(stateTimeout)
+	for time.Now().Before(deadline) {
+		done, err := client.IsImageReplicatedTo(imageId, datacenter)
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+
+	return fmt.Errorf("timed out waiting for replication to %s", datacenter)
+}
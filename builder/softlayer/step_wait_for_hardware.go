@@ -0,0 +1,49 @@
+package softlayer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+)
+
+// stepWaitforHardware polls a bare metal order until SoftLayer reports the
+// server provisioned, then stores its primary IP in "instance_ip" so the
+// rest of the step chain (commHost and the communicator steps) can stay
+// oblivious to whether the build is virtual or bare metal.
+type stepWaitforHardware struct{}
+
+func (s *stepWaitforHardware) Run(state multistep.StateBag) multistep.StepAction {
+	config := state.Get("config").(config)
+	client := state.Get("client").(*SoftlayerClient)
+	ui := state.Get("ui").(packer.Ui)
+	hardwareId := state.Get("hardware_id").(string)
+
+	ui.Say("Waiting for bare metal server to come online...")
+
+	deadline := time.Now().Add(config.StateTimeout)
+	for time.Now().Before(deadline) {
+		ready, ip, err := client.GetHardwareStatus(hardwareId)
+		if err != nil {
+			err := fmt.Errorf("Error checking hardware server status: %s", err)
+			state.Put("error", err)
+			ui.Error(err.Error())
+			return multistep.ActionHalt
+		}
+
+		if ready {
+			state.Put("instance_ip", ip)
+			return multistep.ActionContinue
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+
+	err := fmt.Errorf("timed out waiting for bare metal server %s to come online", hardwareId)
+	state.Put("error", err)
+	ui.Error(err.Error())
+	return multistep.ActionHalt
+}
+
+func (s *stepWaitforHardware) Cleanup(multistep.StateBag) {}
@@ -0,0 +1,132 @@
+// Package export implements a post-processor that pushes a SoftLayer
+// flex/standard image captured by the softlayer builder to an Object
+// Storage (Swift/S3-compatible) container via
+// Virtual_Guest_Block_Device_Template_Group::copyToExternalSource.
+package export
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jamesdobson/packer-builder-softlayer/builder/softlayer"
+	"github.com/mitchellh/packer/common"
+	"github.com/mitchellh/packer/packer"
+)
+
+// Config is the configuration for the softlayer-export post-processor.
+type Config struct {
+	common.PackerConfig `mapstructure:",squash"`
+
+	SwiftUsername     string `mapstructure:"swift_username"`
+	SwiftAPIKey       string `mapstructure:"swift_api_key"`
+	SwiftContainer    string `mapstructure:"swift_container"`
+	SwiftCluster      string `mapstructure:"swift_cluster"`
+	TargetPath        string `mapstructure:"target_path"`
+	KeepInputArtifact bool   `mapstructure:"keep_input_artifact"`
+
+	tpl *packer.ConfigTemplate
+}
+
+// exportableImage is implemented by the SoftLayer client that the softlayer
+// builder's artifact returns from State("Client"), since packer.Artifact
+// itself exposes no SoftLayer-specific behavior.
+type exportableImage interface {
+	CopyToExternalSource(imageId, username, apiKey, container, cluster string) error
+	DownloadFromExternalSource(container, objectName, destPath string) error
+}
+
+// PostProcessor pushes a softlayer builder artifact's image to Swift/S3.
+type PostProcessor struct {
+	config Config
+}
+
+func (p *PostProcessor) Configure(raws ...interface{}) error {
+	_, err := common.DecodeConfig(&p.config, raws...)
+	if err != nil {
+		return err
+	}
+
+	p.config.tpl, err = packer.NewConfigTemplate()
+	if err != nil {
+		return err
+	}
+	p.config.tpl.UserVars = p.config.PackerUserVars
+
+	templates := map[string]*string{
+		"swift_username":  &p.config.SwiftUsername,
+		"swift_api_key":   &p.config.SwiftAPIKey,
+		"swift_container": &p.config.SwiftContainer,
+		"swift_cluster":   &p.config.SwiftCluster,
+	}
+
+	errs := &packer.MultiError{}
+	for n, ptr := range templates {
+		var err error
+		*ptr, err = p.config.tpl.Process(*ptr, nil)
+		if err != nil {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("Error processing %s: %s", n, err))
+		}
+	}
+
+	if p.config.SwiftUsername == "" {
+		errs = packer.MultiErrorAppend(errs, errors.New("swift_username must be specified"))
+	}
+
+	if p.config.SwiftAPIKey == "" {
+		errs = packer.MultiErrorAppend(errs, errors.New("swift_api_key must be specified"))
+	}
+
+	if p.config.SwiftContainer == "" {
+		errs = packer.MultiErrorAppend(errs, errors.New("swift_container must be specified"))
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (p *PostProcessor) PostProcess(ui packer.Ui, artifact packer.Artifact) (packer.Artifact, bool, error) {
+	if artifact.BuilderId() != softlayer.BuilderId {
+		return nil, false, fmt.Errorf(
+			"Unsupported artifact type %s. Can only export artifacts from the SoftLayer builder.",
+			artifact.BuilderId())
+	}
+
+	image, ok := artifact.State("Client").(exportableImage)
+	if !ok {
+		return nil, false, errors.New("artifact does not support exporting to external storage")
+	}
+
+	if datacenters, ok := artifact.State("Datacenters").([]string); ok && len(datacenters) > 1 {
+		return nil, false, fmt.Errorf(
+			"Cannot export artifact %s: it was replicated to %d datacenters (%v), and "+
+				"softlayer-export does not yet support exporting multi-datacenter artifacts. "+
+				"Build without image_replication_datacenters to export.",
+			artifact.Id(), len(datacenters), datacenters)
+	}
+
+	imageId := artifact.Id()
+
+	ui.Say(fmt.Sprintf("Exporting SoftLayer image %s to Swift container %s...", imageId, p.config.SwiftContainer))
+
+	err := image.CopyToExternalSource(
+		imageId, p.config.SwiftUsername, p.config.SwiftAPIKey, p.config.SwiftContainer, p.config.SwiftCluster)
+	if err != nil {
+		return nil, false, fmt.Errorf("Error exporting image: %s", err)
+	}
+
+	ui.Say("Export complete.")
+
+	if p.config.TargetPath != "" {
+		ui.Say(fmt.Sprintf("Downloading exported image to %s...", p.config.TargetPath))
+
+		err := image.DownloadFromExternalSource(p.config.SwiftContainer, imageId, p.config.TargetPath)
+		if err != nil {
+			return nil, false, fmt.Errorf("Error downloading exported image: %s", err)
+		}
+	}
+
+	return artifact, p.config.KeepInputArtifact, nil
+}